@@ -0,0 +1,41 @@
+// Copyright 2019 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package frontend
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+)
+
+// wantsJSON reports whether r is asking for the JSON representation of a
+// details page, either via the Accept header or the "format" query
+// parameter. This lets tooling (editors, CI bots, dashboards) consume tab
+// data without scraping HTML.
+func wantsJSON(r *http.Request) bool {
+	if r.URL.Query().Get("format") == "json" {
+		return true
+	}
+	for _, accept := range r.Header["Accept"] {
+		if strings.Contains(accept, "application/json") {
+			return true
+		}
+	}
+	return false
+}
+
+// serveDetailsJSON writes details, the raw struct returned by one of the
+// fetchDetailsFor* functions, as JSON instead of rendering it into an HTML
+// template. Each *Details type carries `json` struct tags so that its field
+// names are a stable part of the API surface, independent of the Go
+// identifiers used to render templates.
+func (s *Server) serveDetailsJSON(w http.ResponseWriter, status int, details interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(details); err != nil {
+		log.Printf("json.NewEncoder.Encode(%v): %v", details, err)
+	}
+}
@@ -0,0 +1,61 @@
+// Copyright 2019 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package frontend
+
+import "golang.org/x/pkgsite/internal/licenses"
+
+// DocumentationDetails contains the data used to render the doc tab,
+// returned by fetchDocumentationDetails and legacyFetchDocumentationDetails.
+type DocumentationDetails struct {
+	GOOS          string `json:"goos"`
+	GOARCH        string `json:"goarch"`
+	Documentation string `json:"documentation"`
+}
+
+// VersionsDetails contains the data used to render the versions tab,
+// returned by fetchPackageVersionsDetails and fetchModuleVersionsDetails.
+type VersionsDetails struct {
+	// ThisModule lists the versions of the module containing the
+	// package or module being viewed, grouped by major version.
+	ThisModule []*MajorVersionGroup `json:"this_module"`
+
+	// OtherModules lists module paths that also provide this package,
+	// at a different major version suffix.
+	OtherModules []string `json:"other_modules,omitempty"`
+}
+
+// MajorVersionGroup groups the minor/patch versions released under a
+// single major version, newest first.
+type MajorVersionGroup struct {
+	Major    string   `json:"major"`
+	Versions []string `json:"versions"`
+}
+
+// ImportsDetails contains the data used to render the imports tab,
+// returned by fetchImportsDetails.
+type ImportsDetails struct {
+	ModulePath      string   `json:"module_path"`
+	Imports         []string `json:"imports,omitempty"`
+	ExternalImports []string `json:"external_imports,omitempty"`
+}
+
+// ImportedByDetails contains the data used to render the imported-by tab,
+// returned by fetchImportedByDetails.
+type ImportedByDetails struct {
+	ModulePath string   `json:"module_path"`
+	ImportedBy []string `json:"imported_by,omitempty"`
+}
+
+// LicensesDetails contains the data used to render the licenses tab,
+// returned by legacyFetchPackageLicensesDetails.
+type LicensesDetails struct {
+	Licenses []*License `json:"licenses,omitempty"`
+}
+
+// License pairs a license's metadata with its full text for display.
+type License struct {
+	*licenses.Metadata
+	Contents string `json:"contents"`
+}
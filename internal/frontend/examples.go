@@ -0,0 +1,177 @@
+// Copyright 2019 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package frontend
+
+import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	"fmt"
+	goDoc "go/doc"
+	"go/format"
+	"go/token"
+	"sync"
+
+	"golang.org/x/pkgsite/internal"
+)
+
+// Example is a single runnable documentation example, ready to render with
+// its input, expected output, and a "Run in Playground" link.
+type Example struct {
+	// Name is the example's identifier, e.g. "Foo_bar" for
+	// func ExampleFoo_bar().
+	Name string `json:"name"`
+
+	// ParentName is the name of the function or type the example
+	// documents, or the empty string for a package-level example.
+	ParentName string `json:"parent_name,omitempty"`
+
+	// Doc is the example's doc comment.
+	Doc string `json:"doc,omitempty"`
+
+	// Code is the formatted source of the example's body.
+	Code string `json:"code"`
+
+	// Output is the example's expected output, or the empty string if the
+	// example has no Output comment.
+	Output string `json:"output,omitempty"`
+
+	// Play is the formatted source of the complete, compilable program
+	// (package declaration, imports, and all) to submit to the
+	// playground's share endpoint. It's empty unless Playable is true.
+	Play string `json:"play,omitempty"`
+
+	// Playable reports whether the example can be submitted to the
+	// playground: it's true when go/doc could synthesize a complete
+	// program for the example (its Play field was set), and false
+	// otherwise, e.g. for examples that use dot imports or refer to
+	// identifiers go/doc cannot resolve.
+	Playable bool `json:"playable"`
+}
+
+// ExamplesDetails contains the data used to render the examples tab.
+type ExamplesDetails struct {
+	Examples []*Example `json:"examples"`
+}
+
+// exampleCacheKey identifies a single rendered example across requests, so
+// that repeated views of the same module version don't re-format it.
+type exampleCacheKey struct {
+	modulePath, version, exampleName string
+}
+
+var exampleCache sync.Map // exampleCacheKey -> *Example
+
+// fetchExamplesDetails extracts the Example* functions from the package's
+// documentation and renders them for the examples tab. It reuses the
+// documentation already computed for the doc tab (vdir.Package.Documentation)
+// rather than re-parsing source, and caches the rendered result per
+// (modulePath, version, exampleName), since formatting an example's code is
+// the expensive part of this path.
+func fetchExamplesDetails(ctx context.Context, vdir *internal.VersionedDirectory) (*ExamplesDetails, error) {
+	docPkg, fset, err := decodeDocumentationPackage(vdir.Package.Documentation)
+	if err != nil {
+		return nil, fmt.Errorf("decodeDocumentationPackage(vdir.Package.Documentation): %v", err)
+	}
+
+	var examples []*Example
+	for _, raw := range collectRawExamples(docPkg) {
+		key := exampleCacheKey{vdir.ModulePath, vdir.Version, raw.name}
+		if cached, ok := exampleCache.Load(key); ok {
+			examples = append(examples, cached.(*Example))
+			continue
+		}
+		ex, err := renderExample(raw, fset)
+		if err != nil {
+			return nil, fmt.Errorf("renderExample(%q): %v", raw.name, err)
+		}
+		exampleCache.Store(key, ex)
+		examples = append(examples, ex)
+	}
+	return &ExamplesDetails{Examples: examples}, nil
+}
+
+// decodeDocumentationPackage decodes the *token.FileSet and go/doc.Package
+// gob-encoded in d's Source field, as produced when the package's
+// documentation was fetched. The two are encoded as a pair, in that order,
+// on the same stream: the AST nodes under docPkg.Examples[].Code carry
+// only token.Pos offsets, which are meaningless without the FileSet that
+// was current when those offsets were recorded, so it has to travel with
+// the package rather than be reconstructed fresh.
+func decodeDocumentationPackage(d *internal.Documentation) (*goDoc.Package, *token.FileSet, error) {
+	dec := gob.NewDecoder(bytes.NewReader(d.Source))
+	fset := token.NewFileSet()
+	if err := fset.Read(dec.Decode); err != nil {
+		return nil, nil, fmt.Errorf("gob decode FileSet: %v", err)
+	}
+	var docPkg goDoc.Package
+	if err := dec.Decode(&docPkg); err != nil {
+		return nil, nil, fmt.Errorf("gob decode Package: %v", err)
+	}
+	return &docPkg, fset, nil
+}
+
+// rawExample pairs a go/doc.Example with the name under which it should be
+// displayed.
+type rawExample struct {
+	name       string
+	parentName string
+	example    *goDoc.Example
+}
+
+// collectRawExamples gathers every Example* function documented for the
+// package: package-level examples, and examples attached to functions,
+// types, and methods.
+func collectRawExamples(pkg *goDoc.Package) []rawExample {
+	var raws []rawExample
+	for _, ex := range pkg.Examples {
+		raws = append(raws, rawExample{name: ex.Name, example: ex})
+	}
+	for _, f := range pkg.Funcs {
+		for _, ex := range f.Examples {
+			raws = append(raws, rawExample{name: ex.Name, parentName: f.Name, example: ex})
+		}
+	}
+	for _, t := range pkg.Types {
+		for _, ex := range t.Examples {
+			raws = append(raws, rawExample{name: ex.Name, parentName: t.Name, example: ex})
+		}
+		for _, f := range t.Funcs {
+			for _, ex := range f.Examples {
+				raws = append(raws, rawExample{name: ex.Name, parentName: f.Name, example: ex})
+			}
+		}
+		for _, m := range t.Methods {
+			for _, ex := range m.Examples {
+				raws = append(raws, rawExample{name: ex.Name, parentName: t.Name + "." + m.Name, example: ex})
+			}
+		}
+	}
+	return raws
+}
+
+// renderExample formats a single go/doc.Example for display.
+func renderExample(raw rawExample, fset *token.FileSet) (*Example, error) {
+	var buf bytes.Buffer
+	if err := format.Node(&buf, fset, raw.example.Code); err != nil {
+		return nil, fmt.Errorf("format.Node: %v", err)
+	}
+	ex := &Example{
+		Name:       raw.name,
+		ParentName: raw.parentName,
+		Doc:        raw.example.Doc,
+		Code:       buf.String(),
+		Output:     raw.example.Output,
+	}
+	if raw.example.Play != nil {
+		var playBuf bytes.Buffer
+		if err := format.Node(&playBuf, fset, raw.example.Play); err != nil {
+			return nil, fmt.Errorf("format.Node(play): %v", err)
+		}
+		ex.Play = playBuf.String()
+		ex.Playable = true
+	}
+	return ex, nil
+}
@@ -0,0 +1,53 @@
+// Copyright 2019 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package frontend
+
+import (
+	"io"
+	"log"
+	"net/http"
+	"time"
+)
+
+// playShareURL is the upstream playground endpoint that turns a program
+// into a shareable snippet ID.
+const playShareURL = "https://play.golang.org/share"
+
+// playClient is used for the one outgoing request handlePlayShare makes
+// per call; a short timeout keeps a slow or unreachable play.golang.org
+// from tying up the handler goroutine.
+var playClient = &http.Client{Timeout: 10 * time.Second}
+
+// handlePlayShare proxies a "Run in Playground" request to
+// play.golang.org/share on the server's behalf. play.golang.org does not
+// send a permissive Access-Control-Allow-Origin header, so a browser
+// fetch() to it directly from the pkgsite origin is blocked by CORS; going
+// through this same-origin endpoint avoids that, which is also why the
+// real playground frontend proxies /share through its own server.
+func (s *Server) handlePlayShare(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	req, err := http.NewRequestWithContext(r.Context(), http.MethodPost, playShareURL, r.Body)
+	if err != nil {
+		log.Printf("http.NewRequestWithContext(ctx, POST, %q): %v", playShareURL, err)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	resp, err := playClient.Do(req)
+	if err != nil {
+		log.Printf("playClient.Do(%q): %v", playShareURL, err)
+		http.Error(w, "playground unavailable", http.StatusBadGateway)
+		return
+	}
+	defer resp.Body.Close()
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(resp.StatusCode)
+	if _, err := io.Copy(w, resp.Body); err != nil {
+		log.Printf("io.Copy(w, resp.Body): %v", err)
+	}
+}
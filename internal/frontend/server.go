@@ -9,33 +9,49 @@ import (
 	"fmt"
 	"html/template"
 	"io"
+	"io/fs"
 	"log"
 	"net/http"
+	"os"
 	"path/filepath"
+	"strings"
 	"sync"
 	"time"
 
+	"github.com/fsnotify/fsnotify"
+	"golang.org/x/discovery/internal"
 	"golang.org/x/discovery/internal/middleware"
 	"golang.org/x/discovery/internal/postgres"
 )
 
+// templateWatchDebounce is how long the template watcher waits after the
+// last filesystem event before re-parsing, so that a burst of saves (e.g.
+// from an editor, or a branch switch touching many files) triggers a
+// single reparse instead of one per file.
+const templateWatchDebounce = 100 * time.Millisecond
+
 // Server handles requests for the various frontend pages.
 type Server struct {
 	http.Handler
 
-	db              *postgres.DB
-	templateDir     string
-	reloadTemplates bool
-	errorPage       []byte
+	db          *postgres.DB
+	tabs        *TabRegistry
+	templateDir string
+	errorPage   []byte
 
 	mu        sync.RWMutex // Protects all fields below
 	templates map[string]*template.Template
 }
 
 // New creates a new Server for the given database and template directory.
-// reloadTemplates should be used during development when it can be helpful to
-// reload templates from disk each time a page is loaded.
-func NewServer(db *postgres.DB, staticPath string, reloadTemplates bool) (*Server, error) {
+// reloadTemplates should be used during development: it starts a watcher
+// that re-parses the template tree whenever a file under templateDir
+// changes, instead of requiring a server restart. registry supplies the
+// tabs available on the package, module, and directory views; pass
+// NewDefaultTabRegistry(nil) for the built-in set with the security tab
+// disabled, or register a VulnSource-backed one to enable it. Operators can
+// also register entirely new tabs on registry before it's passed in.
+func NewServer(db *postgres.DB, staticPath string, reloadTemplates bool, registry *TabRegistry) (*Server, error) {
 	templateDir := filepath.Join(staticPath, "html")
 	ts, err := parsePageTemplates(templateDir)
 	if err != nil {
@@ -44,11 +60,11 @@ func NewServer(db *postgres.DB, staticPath string, reloadTemplates bool) (*Serve
 
 	mux := http.NewServeMux()
 	s := &Server{
-		Handler:         mux,
-		db:              db,
-		templateDir:     templateDir,
-		reloadTemplates: reloadTemplates,
-		templates:       ts,
+		Handler:     mux,
+		db:          db,
+		tabs:        registry,
+		templateDir: templateDir,
+		templates:   ts,
 	}
 	errorPageBytes, err := s.renderErrorPage(http.StatusInternalServerError, nil)
 	if err != nil {
@@ -56,11 +72,18 @@ func NewServer(db *postgres.DB, staticPath string, reloadTemplates bool) (*Serve
 	}
 	s.errorPage = errorPageBytes
 
+	if reloadTemplates {
+		if err := s.watchTemplates(); err != nil {
+			return nil, fmt.Errorf("s.watchTemplates(): %v", err)
+		}
+	}
+
 	mux.Handle("/static/", http.StripPrefix("/static/", http.FileServer(http.Dir(staticPath))))
 	mux.HandleFunc("/favicon.ico", func(w http.ResponseWriter, r *http.Request) {
 		http.ServeFile(w, r, fmt.Sprintf("%s/img/favicon.ico", http.Dir(staticPath)))
 	})
 	mux.Handle("/pkg/", http.StripPrefix("/pkg", http.HandlerFunc(s.handleDetails)))
+	mux.HandleFunc("/play/share", s.handlePlayShare)
 	mux.HandleFunc("/search", s.handleSearch)
 	mux.HandleFunc("/license-policy", s.handleStaticPage("license_policy.tmpl", "Licenses"))
 	mux.HandleFunc("/", s.handleStaticPage("index.tmpl", "Go Discovery"))
@@ -68,6 +91,90 @@ func NewServer(db *postgres.DB, staticPath string, reloadTemplates bool) (*Serve
 	return s, nil
 }
 
+// watchTemplates starts an fsnotify watcher on s.templateDir and every
+// directory nested under it (fsnotify only watches the directory it's
+// given, not its descendants), and atomically swaps s.templates under
+// s.mu whenever any of them change. This replaces re-parsing the full
+// template tree under the write lock on every request, which meant every
+// page view during development paid for disk I/O and lock contention; with
+// the watcher, a parse error is logged once when it happens rather than on
+// every subsequent page view.
+func (s *Server) watchTemplates() error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("fsnotify.NewWatcher(): %v", err)
+	}
+	if err := addTemplateDirs(watcher, s.templateDir); err != nil {
+		watcher.Close()
+		return err
+	}
+
+	go func() {
+		var debounce *time.Timer
+		for {
+			select {
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				// A newly created subdirectory (e.g. from a "git checkout"
+				// of a branch that added one) needs to be added
+				// explicitly, so that files created under it are seen.
+				if event.Op&fsnotify.Create != 0 {
+					if fi, err := os.Stat(event.Name); err == nil && fi.IsDir() {
+						if err := addTemplateDirs(watcher, event.Name); err != nil {
+							log.Printf("template watcher: %v", err)
+						}
+					}
+				}
+				if debounce == nil {
+					debounce = time.AfterFunc(templateWatchDebounce, s.reloadTemplates)
+				} else {
+					debounce.Reset(templateWatchDebounce)
+				}
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				log.Printf("template watcher: %v", err)
+			}
+		}
+	}()
+	return nil
+}
+
+// addTemplateDirs adds root and every directory beneath it to watcher.
+// fsnotify.Watcher.Add is not recursive, so a tree with nested
+// subdirectories (helpers/, pages/, or anything added under them later)
+// needs each directory added individually.
+func addTemplateDirs(watcher *fsnotify.Watcher, root string) error {
+	return filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		if err := watcher.Add(path); err != nil {
+			return fmt.Errorf("watcher.Add(%q): %v", path, err)
+		}
+		return nil
+	})
+}
+
+// reloadTemplates re-parses s.templateDir and, on success, atomically
+// swaps the result into s.templates.
+func (s *Server) reloadTemplates() {
+	ts, err := parsePageTemplates(s.templateDir)
+	if err != nil {
+		log.Printf("error parsing templates: %v", err)
+		return
+	}
+	s.mu.Lock()
+	s.templates = ts
+	s.mu.Unlock()
+}
+
 // handleStaticPage handles requests to a template that contains no dynamic
 // content.
 func (s *Server) handleStaticPage(templateName, title string) http.HandlerFunc {
@@ -127,18 +234,6 @@ func (s *Server) renderErrorPage(status int, page *errorPage) ([]byte, error) {
 
 // servePage is used to execute all templates for a *Server.
 func (s *Server) servePage(w http.ResponseWriter, templateName string, page interface{}) {
-	if s.reloadTemplates {
-		s.mu.Lock()
-		var err error
-		s.templates, err = parsePageTemplates(s.templateDir)
-		s.mu.Unlock()
-		if err != nil {
-			log.Printf("Error parsing templates: %v", err)
-			w.WriteHeader(http.StatusInternalServerError)
-			return
-		}
-	}
-
 	buf, err := s.renderPage(templateName, page)
 	if err != nil {
 		log.Printf("s.renderPage(%q, %v): %v", templateName, page, err)
@@ -176,11 +271,13 @@ func parsePageTemplates(base string) (map[string]*template.Template, error) {
 		{"search.tmpl"},
 		{"license_policy.tmpl"},
 		{"doc.tmpl", "details.tmpl"},
+		{"examples.tmpl", "details.tmpl"},
 		{"importedby.tmpl", "details.tmpl"},
 		{"imports.tmpl", "details.tmpl"},
 		{"licenses.tmpl", "details.tmpl"},
 		{"module.tmpl", "details.tmpl"},
 		{"overview.tmpl", "details.tmpl"},
+		{"security.tmpl", "details.tmpl"},
 		{"versions.tmpl", "details.tmpl"},
 	}
 
@@ -214,4 +311,87 @@ func parsePageTemplates(base string) (map[string]*template.Template, error) {
 		templates[set[0]] = t
 	}
 	return templates, nil
+}
+
+// detailsPage contains fields common to every rendered package/module/
+// directory tab.
+type detailsPage struct {
+	basePageData
+	Settings TabSettings
+	Tabs     []TabSettings // every tab on this view, in nav order, for rendering the tab bar
+	Details  interface{}
+}
+
+// handleDetails handles requests for a package's, module's, or directory's
+// details tabs, e.g. /pkg/encoding/json?tab=doc. It serves the rendered
+// HTML template for the tab by default, or the tab's raw detail struct as
+// JSON when the request asks for application/json (via the Accept header
+// or ?format=json), so that tooling can consume any tab's data, on any of
+// the three views, without scraping HTML. Dispatch to the registered
+// FetchFunc goes entirely through s.tabs; there is no switch statement
+// here to keep in sync as tabs are added or removed.
+func (s *Server) handleDetails(w http.ResponseWriter, r *http.Request) {
+	fullPath := strings.TrimPrefix(r.URL.Path, "/")
+	version := internal.LatestVersion
+	if i := strings.IndexByte(fullPath, '@'); i != -1 {
+		fullPath, version = fullPath[:i], fullPath[i+1:]
+	}
+
+	vdir, err := s.db.GetDirectory(r.Context(), fullPath, internal.UnknownModulePath, version)
+	if err != nil {
+		log.Printf("GetDirectory(ctx, %q, UnknownModulePath, %q): %v", fullPath, version, err)
+		s.serveErrorPage(w, r, http.StatusNotFound, nil)
+		return
+	}
+
+	tab := r.FormValue("tab")
+	if tab == "" {
+		tab = "doc"
+	}
+
+	var settings TabSettings
+	var tabs []TabSettings
+	var ok bool
+	var fetch func(r *http.Request, tab string, ds internal.DataSource, vdir *internal.VersionedDirectory) (interface{}, error)
+	switch {
+	case vdir.Package != nil:
+		settings, ok = s.tabs.PackageTab(tab)
+		tabs = s.tabs.PackageTabSettings()
+		fetch = s.tabs.FetchPackageDetails
+	case vdir.Path == vdir.ModulePath:
+		settings, ok = s.tabs.ModuleTab(tab)
+		tabs = s.tabs.ModuleTabSettings()
+		fetch = s.tabs.FetchModuleDetails
+	default:
+		settings, ok = s.tabs.DirectoryTab(tab)
+		tabs = s.tabs.DirectoryTabSettings()
+		fetch = s.tabs.FetchDirectoryDetails
+	}
+	if !ok {
+		s.serveErrorPage(w, r, http.StatusBadRequest, nil)
+		return
+	}
+
+	details, err := fetch(r, tab, s.db, vdir)
+	if err != nil {
+		log.Printf("fetch details for %q tab %q: %v", fullPath, tab, err)
+		s.serveErrorPage(w, r, http.StatusInternalServerError, nil)
+		return
+	}
+
+	if wantsJSON(r) {
+		s.serveDetailsJSON(w, http.StatusOK, details)
+		return
+	}
+
+	nonce, ok := middleware.GetNonce(r.Context())
+	if !ok {
+		log.Printf("middleware.GetNonce(r.Context()): nonce was not set")
+	}
+	s.servePage(w, settings.TemplateName, detailsPage{
+		basePageData: basePageData{Title: fullPath, Nonce: nonce},
+		Settings:     settings,
+		Tabs:         tabs,
+		Details:      details,
+	})
 }
\ No newline at end of file
@@ -5,10 +5,12 @@
 package frontend
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"net/url"
 	"strings"
+	"sync"
 
 	"golang.org/x/pkgsite/internal"
 	"golang.org/x/pkgsite/internal/licenses"
@@ -35,112 +37,372 @@ type TabSettings struct {
 	Disabled bool
 }
 
-var (
-	packageTabSettings = []TabSettings{
-		{
-			Name:         "doc",
-			DisplayName:  "Doc",
-			TemplateName: "pkg_doc.tmpl",
-		},
-		{
-			Name:              "overview",
-			AlwaysShowDetails: true,
-			DisplayName:       "Overview",
-			TemplateName:      "overview.tmpl",
-		},
-		{
-			Name:              "subdirectories",
-			AlwaysShowDetails: true,
-			DisplayName:       "Subdirectories",
-			TemplateName:      "subdirectories.tmpl",
-		},
-		{
-			Name:              "versions",
-			AlwaysShowDetails: true,
-			DisplayName:       "Versions",
-			TemplateName:      "versions.tmpl",
-		},
-		{
-			Name:              "imports",
-			DisplayName:       "Imports",
-			AlwaysShowDetails: true,
-			TemplateName:      "pkg_imports.tmpl",
-		},
-		{
-			Name:              "importedby",
-			DisplayName:       "Imported By",
-			AlwaysShowDetails: true,
-			TemplateName:      "pkg_importedby.tmpl",
-		},
-		{
-			Name:         "licenses",
-			DisplayName:  "Licenses",
-			TemplateName: "licenses.tmpl",
-		},
+// FetchFunc fetches the details rendered by a single tab on the package,
+// module, or directory view.
+type FetchFunc func(r *http.Request, ds internal.DataSource, vdir *internal.VersionedDirectory) (interface{}, error)
+
+// EnabledFunc reports whether a tab's details may be fetched for the given
+// directory; fetching a tab for which it returns false fails with an
+// error. A nil EnabledFunc means the tab can always be fetched. It does
+// not affect PackageTabSettings, ModuleTabSettings, DirectoryTabSettings,
+// or the *Tab lookups, which include the tab either way.
+type EnabledFunc func(ctx context.Context, vdir *internal.VersionedDirectory) bool
+
+// TabOption configures a tab at registration time.
+type TabOption func(*tabEntry)
+
+// EnabledWhen restricts a tab to directories for which enabled returns true.
+func EnabledWhen(enabled EnabledFunc) TabOption {
+	return func(e *tabEntry) { e.Enabled = enabled }
+}
+
+type tabEntry struct {
+	Settings TabSettings
+	Fetch    FetchFunc
+	Enabled  EnabledFunc
+}
+
+// TabRegistry holds the tabs available for the package, module, and
+// directory views. The default registry (see NewDefaultTabRegistry)
+// matches the built-in pkgsite tabs; downstream forks can register
+// additional tabs, such as "security", "benchmarks", or "examples",
+// without patching the dispatch logic in this file.
+//
+// A TabRegistry is safe for concurrent use.
+type TabRegistry struct {
+	mu          sync.RWMutex
+	packages    []*tabEntry
+	modules     []*tabEntry
+	directories []*tabEntry
+}
+
+// NewTabRegistry returns an empty TabRegistry. Most callers want
+// NewDefaultTabRegistry, which starts from the built-in pkgsite tabs.
+func NewTabRegistry() *TabRegistry {
+	return &TabRegistry{}
+}
+
+// RegisterPackageTab adds, or replaces, a tab on the package view.
+func (tr *TabRegistry) RegisterPackageTab(settings TabSettings, fetch FetchFunc, opts ...TabOption) {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	tr.packages = registerTab(tr.packages, settings, fetch, opts)
+}
+
+// RegisterModuleTab adds, or replaces, a tab on the module view.
+func (tr *TabRegistry) RegisterModuleTab(settings TabSettings, fetch FetchFunc, opts ...TabOption) {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	tr.modules = registerTab(tr.modules, settings, fetch, opts)
+}
+
+// RegisterDirectoryTab adds, or replaces, a tab on the directory view. The
+// directory view always lists every package tab in its navigation (for
+// visual consistency with the package view), but only tabs registered here
+// are enabled; the rest are rendered disabled. See DirectoryTabSettings.
+func (tr *TabRegistry) RegisterDirectoryTab(settings TabSettings, fetch FetchFunc, opts ...TabOption) {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	tr.directories = registerTab(tr.directories, settings, fetch, opts)
+}
+
+func registerTab(tabs []*tabEntry, settings TabSettings, fetch FetchFunc, opts []TabOption) []*tabEntry {
+	e := &tabEntry{Settings: settings, Fetch: fetch}
+	for _, opt := range opts {
+		opt(e)
 	}
-	packageTabLookup = make(map[string]TabSettings)
-
-	directoryTabSettings = make([]TabSettings, len(packageTabSettings))
-	directoryTabLookup   = make(map[string]TabSettings)
-
-	moduleTabSettings = []TabSettings{
-		{
-			Name:              "overview",
-			AlwaysShowDetails: true,
-			DisplayName:       "Overview",
-			TemplateName:      "overview.tmpl",
-		},
-		{
-			Name:              "packages",
-			AlwaysShowDetails: true,
-			DisplayName:       "Packages",
-			TemplateName:      "subdirectories.tmpl",
-		},
-		{
-			Name:              "versions",
-			AlwaysShowDetails: true,
-			DisplayName:       "Versions",
-			TemplateName:      "versions.tmpl",
-		},
-		{
-			Name:         "licenses",
-			DisplayName:  "Licenses",
-			TemplateName: "licenses.tmpl",
-		},
+	for i, existing := range tabs {
+		if existing.Settings.Name == settings.Name {
+			tabs[i] = e
+			return tabs
+		}
 	}
-	moduleTabLookup = make(map[string]TabSettings)
-)
+	return append(tabs, e)
+}
+
+// PackageTabSettings returns the tabs registered for the package view, in
+// display order.
+func (tr *TabRegistry) PackageTabSettings() []TabSettings {
+	tr.mu.RLock()
+	defer tr.mu.RUnlock()
+	return settingsOf(tr.packages)
+}
+
+// ModuleTabSettings returns the tabs registered for the module view, in
+// display order.
+func (tr *TabRegistry) ModuleTabSettings() []TabSettings {
+	tr.mu.RLock()
+	defer tr.mu.RUnlock()
+	return settingsOf(tr.modules)
+}
+
+// PackageTab reports the settings registered for the named tab on the
+// package view.
+func (tr *TabRegistry) PackageTab(name string) (TabSettings, bool) {
+	tr.mu.RLock()
+	defer tr.mu.RUnlock()
+	return tabByName(tr.packages, name)
+}
+
+// ModuleTab reports the settings registered for the named tab on the
+// module view.
+func (tr *TabRegistry) ModuleTab(name string) (TabSettings, bool) {
+	tr.mu.RLock()
+	defer tr.mu.RUnlock()
+	return tabByName(tr.modules, name)
+}
 
-// validDirectoryTabs indicates if a tab is enabled in the directory view.
-var validDirectoryTabs = map[string]bool{
-	"licenses":       true,
-	"overview":       true,
-	"subdirectories": true,
+// DirectoryTab reports the settings registered for the named tab on the
+// directory view.
+func (tr *TabRegistry) DirectoryTab(name string) (TabSettings, bool) {
+	tr.mu.RLock()
+	defer tr.mu.RUnlock()
+	return tabByName(tr.directories, name)
 }
 
-func init() {
-	for i, ts := range packageTabSettings {
-		// The directory view uses the same design as the packages view
-		// for visual consistency, but some tabs don't make sense, so
-		// we disable them.
-		if !validDirectoryTabs[ts.Name] {
+func tabByName(tabs []*tabEntry, name string) (TabSettings, bool) {
+	for _, e := range tabs {
+		if e.Settings.Name == name {
+			return e.Settings, true
+		}
+	}
+	return TabSettings{}, false
+}
+
+// DirectoryTabSettings returns the tab settings shown in the directory
+// view's navigation, in the same order as the package view. Tabs that
+// have no corresponding RegisterDirectoryTab entry are marked Disabled.
+func (tr *TabRegistry) DirectoryTabSettings() []TabSettings {
+	tr.mu.RLock()
+	defer tr.mu.RUnlock()
+	out := make([]TabSettings, len(tr.packages))
+	for i, e := range tr.packages {
+		ts := e.Settings
+		if !tr.hasDirectoryTabLocked(ts.Name) {
 			ts.Disabled = true
 		}
-		directoryTabSettings[i] = ts
+		out[i] = ts
+	}
+	return out
+}
+
+func (tr *TabRegistry) hasDirectoryTabLocked(name string) bool {
+	for _, e := range tr.directories {
+		if e.Settings.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func settingsOf(tabs []*tabEntry) []TabSettings {
+	out := make([]TabSettings, len(tabs))
+	for i, e := range tabs {
+		out[i] = e.Settings
 	}
-	for _, d := range packageTabSettings {
-		packageTabLookup[d.Name] = d
+	return out
+}
+
+// FetchPackageDetails dispatches to the FetchFunc registered for tab on the
+// package view.
+func (tr *TabRegistry) FetchPackageDetails(r *http.Request, tab string, ds internal.DataSource, vdir *internal.VersionedDirectory) (interface{}, error) {
+	return tr.fetch(&tr.packages, r, tab, ds, vdir)
+}
+
+// FetchModuleDetails dispatches to the FetchFunc registered for tab on the
+// module view.
+func (tr *TabRegistry) FetchModuleDetails(r *http.Request, tab string, ds internal.DataSource, vdir *internal.VersionedDirectory) (interface{}, error) {
+	return tr.fetch(&tr.modules, r, tab, ds, vdir)
+}
+
+// FetchDirectoryDetails dispatches to the FetchFunc registered for tab on
+// the directory view.
+func (tr *TabRegistry) FetchDirectoryDetails(r *http.Request, tab string, ds internal.DataSource, vdir *internal.VersionedDirectory) (interface{}, error) {
+	return tr.fetch(&tr.directories, r, tab, ds, vdir)
+}
+
+func (tr *TabRegistry) fetch(tabs *[]*tabEntry, r *http.Request, tab string, ds internal.DataSource, vdir *internal.VersionedDirectory) (interface{}, error) {
+	tr.mu.RLock()
+	var entry *tabEntry
+	for _, e := range *tabs {
+		if e.Settings.Name == tab {
+			entry = e
+			break
+		}
+	}
+	tr.mu.RUnlock()
+	if entry == nil {
+		return nil, fmt.Errorf("BUG: unable to fetch details: unknown tab %q", tab)
+	}
+	if entry.Enabled != nil && !entry.Enabled(r.Context(), vdir) {
+		return nil, fmt.Errorf("BUG: unable to fetch details: tab %q is not enabled for %s", tab, vdir.Path)
+	}
+	return entry.Fetch(r, ds, vdir)
+}
+
+// NewDefaultTabRegistry returns a TabRegistry populated with the built-in
+// pkgsite tabs. vs is used by the security tab to look up known
+// vulnerabilities; it may be nil to disable that tab.
+func NewDefaultTabRegistry(vs VulnSource) *TabRegistry {
+	tr := NewTabRegistry()
+
+	tr.RegisterPackageTab(TabSettings{
+		Name:         "doc",
+		DisplayName:  "Doc",
+		TemplateName: "pkg_doc.tmpl",
+	}, func(r *http.Request, ds internal.DataSource, vdir *internal.VersionedDirectory) (interface{}, error) {
+		return fetchDocumentationDetails(vdir.Package.Documentation), nil
+	})
+	tr.RegisterPackageTab(TabSettings{
+		Name:              "overview",
+		AlwaysShowDetails: true,
+		DisplayName:       "Overview",
+		TemplateName:      "overview.tmpl",
+	}, defaultOverviewFetch)
+	tr.RegisterPackageTab(TabSettings{
+		Name:              "subdirectories",
+		AlwaysShowDetails: true,
+		DisplayName:       "Subdirectories",
+		TemplateName:      "subdirectories.tmpl",
+	}, defaultSubdirectoriesFetch)
+	tr.RegisterPackageTab(TabSettings{
+		Name:              "versions",
+		AlwaysShowDetails: true,
+		DisplayName:       "Versions",
+		TemplateName:      "versions.tmpl",
+	}, func(r *http.Request, ds internal.DataSource, vdir *internal.VersionedDirectory) (interface{}, error) {
+		return fetchPackageVersionsDetails(r.Context(), ds, vdir.Path, vdir.V1Path, vdir.ModulePath)
+	})
+	tr.RegisterPackageTab(TabSettings{
+		Name:              "imports",
+		DisplayName:       "Imports",
+		AlwaysShowDetails: true,
+		TemplateName:      "pkg_imports.tmpl",
+	}, func(r *http.Request, ds internal.DataSource, vdir *internal.VersionedDirectory) (interface{}, error) {
+		return fetchImportsDetails(r.Context(), ds, vdir.Path, vdir.ModulePath, vdir.Version)
+	})
+	tr.RegisterPackageTab(TabSettings{
+		Name:              "importedby",
+		DisplayName:       "Imported By",
+		AlwaysShowDetails: true,
+		TemplateName:      "pkg_importedby.tmpl",
+	}, func(r *http.Request, ds internal.DataSource, vdir *internal.VersionedDirectory) (interface{}, error) {
+		return fetchImportedByDetails(r.Context(), ds, vdir.Path, vdir.ModulePath)
+	})
+	tr.RegisterPackageTab(TabSettings{
+		Name:         "licenses",
+		DisplayName:  "Licenses",
+		TemplateName: "licenses.tmpl",
+	}, defaultLicensesFetch)
+	tr.RegisterPackageTab(TabSettings{
+		Name:         "examples",
+		DisplayName:  "Examples",
+		TemplateName: "examples.tmpl",
+	}, func(r *http.Request, ds internal.DataSource, vdir *internal.VersionedDirectory) (interface{}, error) {
+		return fetchExamplesDetails(r.Context(), vdir)
+	})
+	if vs != nil {
+		tr.RegisterPackageTab(TabSettings{
+			Name:         "security",
+			DisplayName:  "Security",
+			TemplateName: "security.tmpl",
+		}, defaultSecurityFetch(vs))
 	}
-	for _, d := range directoryTabSettings {
-		directoryTabLookup[d.Name] = d
+
+	tr.RegisterModuleTab(TabSettings{
+		Name:              "overview",
+		AlwaysShowDetails: true,
+		DisplayName:       "Overview",
+		TemplateName:      "overview.tmpl",
+	}, defaultOverviewFetch)
+	tr.RegisterModuleTab(TabSettings{
+		Name:              "packages",
+		AlwaysShowDetails: true,
+		DisplayName:       "Packages",
+		TemplateName:      "subdirectories.tmpl",
+	}, defaultModulePackagesFetch)
+	tr.RegisterModuleTab(TabSettings{
+		Name:              "versions",
+		AlwaysShowDetails: true,
+		DisplayName:       "Versions",
+		TemplateName:      "versions.tmpl",
+	}, func(r *http.Request, ds internal.DataSource, vdir *internal.VersionedDirectory) (interface{}, error) {
+		return fetchModuleVersionsDetails(r.Context(), ds, &vdir.ModuleInfo)
+	})
+	tr.RegisterModuleTab(TabSettings{
+		Name:         "licenses",
+		DisplayName:  "Licenses",
+		TemplateName: "licenses.tmpl",
+	}, defaultLicensesFetch)
+	if vs != nil {
+		tr.RegisterModuleTab(TabSettings{
+			Name:         "security",
+			DisplayName:  "Security",
+			TemplateName: "security.tmpl",
+		}, defaultSecurityFetch(vs))
 	}
-	for _, d := range moduleTabSettings {
-		moduleTabLookup[d.Name] = d
+
+	tr.RegisterDirectoryTab(TabSettings{
+		Name:              "overview",
+		AlwaysShowDetails: true,
+		DisplayName:       "Overview",
+		TemplateName:      "overview.tmpl",
+	}, defaultOverviewFetch)
+	tr.RegisterDirectoryTab(TabSettings{
+		Name:              "subdirectories",
+		AlwaysShowDetails: true,
+		DisplayName:       "Subdirectories",
+		TemplateName:      "subdirectories.tmpl",
+	}, defaultSubdirectoriesFetch)
+	tr.RegisterDirectoryTab(TabSettings{
+		Name:         "licenses",
+		DisplayName:  "Licenses",
+		TemplateName: "licenses.tmpl",
+	}, defaultLicensesFetch)
+
+	return tr
+}
+
+// defaultOverviewFetch is the FetchFunc shared by the "overview" tab on the
+// package, module, and directory views.
+func defaultOverviewFetch(r *http.Request, ds internal.DataSource, vdir *internal.VersionedDirectory) (interface{}, error) {
+	return constructOverviewDetails(r.Context(), &vdir.ModuleInfo, vdir.Readme, vdir.IsRedistributable, urlIsVersioned(r.URL))
+}
+
+// defaultSubdirectoriesFetch is the FetchFunc for the "subdirectories" tab
+// on the package and directory views.
+func defaultSubdirectoriesFetch(r *http.Request, ds internal.DataSource, vdir *internal.VersionedDirectory) (interface{}, error) {
+	return fetchDirectoryDetails(r.Context(), ds, vdir, false)
+}
+
+// defaultModulePackagesFetch is the FetchFunc for the "packages" tab on the
+// module view: it lists every package under the module root.
+func defaultModulePackagesFetch(r *http.Request, ds internal.DataSource, vdir *internal.VersionedDirectory) (interface{}, error) {
+	return fetchDirectoryDetails(r.Context(), ds, vdir, true)
+}
+
+// defaultLicensesFetch is the FetchFunc shared by the "licenses" tab on the
+// package, module, and directory views.
+func defaultLicensesFetch(r *http.Request, ds internal.DataSource, vdir *internal.VersionedDirectory) (interface{}, error) {
+	return legacyFetchPackageLicensesDetails(r.Context(), ds, vdir.Path, vdir.ModulePath, vdir.Version)
+}
+
+// defaultSecurityFetch returns the FetchFunc for the "security" tab, closed
+// over the VulnSource used to look up advisories.
+func defaultSecurityFetch(vs VulnSource) FetchFunc {
+	return func(r *http.Request, ds internal.DataSource, vdir *internal.VersionedDirectory) (interface{}, error) {
+		return fetchSecurityDetails(r.Context(), vs, vdir.ModulePath, vdir.Version)
 	}
 }
 
+func urlIsVersioned(url *url.URL) bool {
+	return strings.ContainsRune(url.Path, '@')
+}
+
 // legacyFetchDetailsForPackage returns tab details by delegating to the correct detail
-// handler.
+// handler. It predates the TabRegistry and is kept switch-based: it is
+// only reachable via the legacy (pre-VersionedDirectory) code path, which
+// is being phased out behind isActiveUseDirectories.
 func legacyFetchDetailsForPackage(r *http.Request, tab string, ds internal.DataSource, pkg *internal.LegacyVersionedPackage) (interface{}, error) {
 	ctx := r.Context()
 	switch tab {
@@ -167,88 +429,9 @@ func legacyFetchDetailsForPackage(r *http.Request, tab string, ds internal.DataS
 	return nil, fmt.Errorf("BUG: unable to fetch details: unknown tab %q", tab)
 }
 
-// fetchDetailsForPackage returns tab details by delegating to the correct detail
-// handler.
-func fetchDetailsForPackage(r *http.Request, tab string, ds internal.DataSource, vdir *internal.VersionedDirectory) (interface{}, error) {
-	ctx := r.Context()
-	switch tab {
-	case "doc":
-		return fetchDocumentationDetails(vdir.Package.Documentation), nil
-	case "overview":
-		return fetchPackageOverviewDetails(ctx, vdir, urlIsVersioned(r.URL))
-	case "subdirectories":
-		return fetchDirectoryDetails(ctx, ds, vdir, false)
-	case "versions":
-		return fetchPackageVersionsDetails(ctx, ds, vdir.Path, vdir.V1Path, vdir.ModulePath)
-	case "imports":
-		return fetchImportsDetails(ctx, ds, vdir.Path, vdir.ModulePath, vdir.Version)
-	case "importedby":
-		return fetchImportedByDetails(ctx, ds, vdir.Path, vdir.ModulePath)
-	case "licenses":
-		return legacyFetchPackageLicensesDetails(ctx, ds, vdir.Path, vdir.ModulePath, vdir.Version)
-	}
-	return nil, fmt.Errorf("BUG: unable to fetch details: unknown tab %q", tab)
-}
-
-func urlIsVersioned(url *url.URL) bool {
-	return strings.ContainsRune(url.Path, '@')
-}
-
-// fetchDetailsForModule returns tab details by delegating to the correct detail
-// handler.
-func fetchDetailsForModule(r *http.Request, tab string, ds internal.DataSource, mi *internal.ModuleInfo, licenses []*licenses.License, readme *internal.Readme) (interface{}, error) {
-	ctx := r.Context()
-	switch tab {
-	case "packages":
-		if isActiveUseDirectories(ctx) {
-			vdir := &internal.VersionedDirectory{
-				ModuleInfo: *mi,
-				Directory: internal.Directory{
-					DirectoryMeta: internal.DirectoryMeta{
-						Path:              mi.ModulePath,
-						V1Path:            mi.SeriesPath(),
-						IsRedistributable: mi.IsRedistributable,
-						Licenses:          licensesToMetadatas(licenses),
-					},
-					Readme: readme,
-				},
-			}
-			return fetchDirectoryDetails(ctx, ds, vdir, true)
-		}
-		return legacyFetchDirectoryDetails(ctx, ds, mi.ModulePath, mi, licensesToMetadatas(licenses), true)
-	case "licenses":
-		return &LicensesDetails{Licenses: transformLicenses(mi.ModulePath, mi.Version, licenses)}, nil
-	case "versions":
-		return fetchModuleVersionsDetails(ctx, ds, mi)
-	case "overview":
-		return constructOverviewDetails(ctx, mi, readme, mi.IsRedistributable, urlIsVersioned(r.URL))
-	}
-	return nil, fmt.Errorf("BUG: unable to fetch details: unknown tab %q", tab)
-}
-
-// fetchDetailsForDirectory returns tab details by delegating to the correct
-// detail handler.
-func fetchDetailsForDirectory(r *http.Request, tab string, ds internal.DataSource, vdir *internal.VersionedDirectory) (interface{}, error) {
-	ctx := r.Context()
-	switch tab {
-	case "overview":
-		return constructOverviewDetails(ctx, &vdir.ModuleInfo, vdir.Readme, vdir.IsRedistributable, urlIsVersioned(r.URL))
-	case "subdirectories":
-		return fetchDirectoryDetails(ctx, ds, vdir, false)
-	case "licenses":
-		// TODO(https://golang.org/issue/40027): replace logic below with
-		// GetLicenses.
-		licenses, err := ds.LegacyGetModuleLicenses(ctx, vdir.ModulePath, vdir.Version)
-		if err != nil {
-			return nil, err
-		}
-		return &LicensesDetails{Licenses: transformLicenses(vdir.ModulePath, vdir.Version, licenses)}, nil
-	}
-	return nil, fmt.Errorf("BUG: unable to fetch details: unknown tab %q", tab)
-}
-
 // legacyFetchDetailsForDirectory returns tab details by delegating to the correct
-// detail handler.
+// detail handler. Like legacyFetchDetailsForPackage, it predates the
+// TabRegistry and is reachable only via the legacy code path.
 func legacyFetchDetailsForDirectory(r *http.Request, tab string, dir *internal.LegacyDirectory, licenses []*licenses.License) (interface{}, error) {
 	switch tab {
 	case "overview":
@@ -256,10 +439,9 @@ func legacyFetchDetailsForDirectory(r *http.Request, tab string, dir *internal.L
 		return constructOverviewDetails(r.Context(), &dir.ModuleInfo, readme, dir.LegacyModuleInfo.IsRedistributable, urlIsVersioned(r.URL))
 	case "subdirectories":
 		// Ideally we would just use fetchDirectoryDetails here so that it
-		// follows the same code path as fetchDetailsForModule and
-		// fetchDetailsForPackage. However, since we already have the directory
-		// and licenses info, it doesn't make sense to call
-		// postgres.GetDirectory again.
+		// follows the same code path as the TabRegistry-based dispatch.
+		// However, since we already have the directory and licenses info,
+		// it doesn't make sense to call postgres.GetDirectory again.
 		return legacyCreateDirectory(dir, licensesToMetadatas(licenses), false)
 	case "licenses":
 		return &LicensesDetails{Licenses: transformLicenses(dir.ModulePath, dir.Version, licenses)}, nil
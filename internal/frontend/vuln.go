@@ -0,0 +1,86 @@
+// Copyright 2019 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package frontend
+
+import (
+	"context"
+
+	"golang.org/x/mod/semver"
+)
+
+// Vulnerability describes a single security advisory affecting a module
+// version, as reported by a VulnSource.
+type Vulnerability struct {
+	// ID is the vulnerability identifier, e.g. a GHSA or GO-ID.
+	ID string `json:"id"`
+
+	// Details is a human-readable description of the vulnerability.
+	Details string `json:"details"`
+
+	// Severity is one of "low", "moderate", "high", or "critical".
+	Severity string `json:"severity"`
+
+	// Introduced is the earliest version affected by the vulnerability, or
+	// the empty string if it has affected every version up to FixedIn.
+	Introduced string `json:"introduced,omitempty"`
+
+	// FixedIn is the earliest version in which the vulnerability is fixed,
+	// or the empty string if no fix is available yet.
+	FixedIn string `json:"fixed_in,omitempty"`
+
+	// Symbols lists the exported symbols affected by the vulnerability, if
+	// the advisory is precise enough to identify them.
+	Symbols []string `json:"symbols,omitempty"`
+}
+
+// VulnSource looks up known vulnerabilities for a module. Implementations
+// may query the Go vulnerability database or an internal mirror of it.
+type VulnSource interface {
+	// GetByModule returns the vulnerabilities known to affect modulePath,
+	// regardless of version. Callers filter by affected version range
+	// themselves using Vulnerability.Introduced and Vulnerability.FixedIn.
+	GetByModule(ctx context.Context, modulePath string) ([]*Vulnerability, error)
+}
+
+// SecurityDetails contains the data used to render the security tab.
+type SecurityDetails struct {
+	// Vulnerabilities affecting the currently displayed version.
+	Vulnerabilities []*Vulnerability `json:"vulnerabilities"`
+}
+
+// fetchSecurityDetails fetches the vulnerabilities known to affect the given
+// module path and filters them down to the ones whose affected range
+// [Introduced, FixedIn) contains version. If vs is nil it returns an empty
+// SecurityDetails; NewDefaultTabRegistry does not register the security
+// tab at all when its VulnSource is nil.
+func fetchSecurityDetails(ctx context.Context, vs VulnSource, modulePath, version string) (*SecurityDetails, error) {
+	if vs == nil {
+		return &SecurityDetails{}, nil
+	}
+	vulns, err := vs.GetByModule(ctx, modulePath)
+	if err != nil {
+		return nil, err
+	}
+	var affected []*Vulnerability
+	for _, v := range vulns {
+		if semver.IsValid(version) && inRange(version, v.Introduced, v.FixedIn) {
+			affected = append(affected, v)
+		}
+	}
+	return &SecurityDetails{Vulnerabilities: affected}, nil
+}
+
+// inRange reports whether version falls in [introduced, fixedIn), treating
+// an empty introduced as "since the beginning" and an empty fixedIn as
+// "never fixed".
+func inRange(version, introduced, fixedIn string) bool {
+	if introduced != "" && semver.Compare(version, introduced) < 0 {
+		return false
+	}
+	if fixedIn != "" && semver.Compare(version, fixedIn) >= 0 {
+		return false
+	}
+	return true
+}